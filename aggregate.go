@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"math/bits"
+	"os"
+	"sort"
+)
+
+type addrRange struct {
+	start, end uint32
+}
+
+// Aggregate collapses adjacent and overlapping CIDR blocks into the minimal
+// covering set of networks — the inverse of CreateSubnets' split direction.
+func Aggregate(nets []Network) []Network {
+	if len(nets) == 0 {
+		return nil
+	}
+
+	ranges := make([]addrRange, len(nets))
+	for i, n := range nets {
+		ranges[i] = addrRange{start: n.network.Addr, end: n.broadcast.Addr}
+	}
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i].start < ranges[j].start })
+
+	merged := make([]addrRange, 0, len(ranges))
+	for _, r := range ranges {
+		if len(merged) > 0 {
+			last := &merged[len(merged)-1]
+			if r.start <= last.end || (last.end != 0xffffffff && r.start == last.end+1) {
+				if r.end > last.end {
+					last.end = r.end
+				}
+				continue
+			}
+		}
+		merged = append(merged, r)
+	}
+
+	var result []Network
+	for _, r := range merged {
+		result = append(result, rangeToCIDRs(r.start, r.end)...)
+	}
+	return result
+}
+
+// rangeToCIDRs splits [start, end] into the fewest possible CIDR blocks:
+// repeatedly emit the largest block whose start is aligned to its size and
+// that still fits within end, then advance past it.
+func rangeToCIDRs(start, end uint32) []Network {
+	var out []Network
+
+	cursor, last := uint64(start), uint64(end)
+	for cursor <= last {
+		maxBits := 32
+		if cursor != 0 {
+			if tz := bits.TrailingZeros64(cursor); tz < maxBits {
+				maxBits = tz
+			}
+		}
+		for maxBits > 0 && (uint64(1)<<uint(maxBits))-1 > last-cursor {
+			maxBits--
+		}
+
+		prefix := uint32(32 - maxBits)
+		out = append(out, *NewNetwork(uint32(cursor), prefixToMask(prefix)))
+
+		cursor += uint64(1) << uint(maxBits)
+	}
+
+	return out
+}
+
+// runAggregate implements `ipcalc aggregate <cidr> <cidr> ...`.
+func runAggregate(args []string) {
+	if len(args) == 0 {
+		checkError(fmt.Errorf("Usage: ipcalc aggregate <cidr> [cidr...]"))
+	}
+
+	nets := make([]Network, 0, len(args))
+	for _, arg := range args {
+		prefix, err := ParsePrefix(arg)
+		checkError(err)
+		if prefix.Addr().Is6() {
+			checkError(fmt.Errorf("aggregate only supports IPv4 CIDRs: %s", arg))
+		}
+		nets = append(nets, *NewNetwork(prefix.Addr().As4(), uint32(prefix.mask().Lo)))
+	}
+
+	for _, n := range Aggregate(nets) {
+		fmt.Fprintf(os.Stdout, "%s/%d\n", n.network.Dots(), bits.OnesCount32(n.mask.Addr))
+	}
+}
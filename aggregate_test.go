@@ -0,0 +1,54 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func aggregateInputs(t *testing.T, cidrs ...string) []Network {
+	t.Helper()
+	nets := make([]Network, len(cidrs))
+	for i, cidr := range cidrs {
+		p := mustPrefix(t, cidr)
+		nets[i] = *NewNetwork(p.addr.As4(), uint32(p.mask().Lo))
+	}
+	return nets
+}
+
+func TestAggregate(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		inputs   []string
+		expected []string
+	}{
+		{
+			desc:     "Three adjacent blocks collapse to one",
+			inputs:   []string{"10.0.0.0/24", "10.0.1.0/24", "10.0.2.0/23"},
+			expected: []string{"10.0.0.0/22"},
+		},
+		{
+			desc:     "Non-adjacent blocks stay separate",
+			inputs:   []string{"10.0.0.0/24", "10.0.5.0/24"},
+			expected: []string{"10.0.0.0/24", "10.0.5.0/24"},
+		},
+		{
+			desc:     "Overlapping blocks collapse",
+			inputs:   []string{"192.168.0.0/23", "192.168.1.0/24"},
+			expected: []string{"192.168.0.0/23"},
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			result := Aggregate(aggregateInputs(t, tC.inputs...))
+
+			actual := make([]string, len(result))
+			for i, n := range result {
+				actual[i] = n.network.Dots() + subnetCIDR(n)
+			}
+
+			assert.Equal(t, tC.expected, actual)
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/bits"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// OutputMode selects how Network.Format renders a network.
+type OutputMode string
+
+const (
+	FormatText OutputMode = "text"
+	FormatJSON OutputMode = "json"
+	FormatYAML OutputMode = "yaml"
+)
+
+// FormatOptions controls both the text layout and which fields are
+// populated in the structured (JSON/YAML) output.
+type FormatOptions struct {
+	Mode             OutputMode
+	PrintDescription bool
+	Extended         bool
+	PrintClass       bool
+}
+
+// ParseOutputMode validates the -o flag's value.
+func ParseOutputMode(s string) (OutputMode, error) {
+	switch OutputMode(s) {
+	case FormatText, FormatJSON, FormatYAML:
+		return OutputMode(s), nil
+	default:
+		return "", fmt.Errorf("unknown output format %q, want text, json or yaml", s)
+	}
+}
+
+// NetworkView is the JSON/YAML projection of a Network. It exists
+// separately from Network so the struct tags don't leak into the
+// unexported fields Network uses for its text rendering.
+type NetworkView struct {
+	Address     string        `json:"address" yaml:"address"`
+	Netmask     string        `json:"netmask" yaml:"netmask"`
+	CIDRPrefix  int           `json:"cidr_prefix" yaml:"cidr_prefix"`
+	Network     string        `json:"network" yaml:"network"`
+	Broadcast   string        `json:"broadcast" yaml:"broadcast"`
+	HostMin     string        `json:"host_min" yaml:"host_min"`
+	HostMax     string        `json:"host_max" yaml:"host_max"`
+	HostsPerNet string        `json:"hosts_per_net" yaml:"hosts_per_net"`
+	Class       string        `json:"class,omitempty" yaml:"class,omitempty"`
+	Subnets     []NetworkView `json:"subnets,omitempty" yaml:"subnets,omitempty"`
+}
+
+func (n *Network) view(printClass bool) NetworkView {
+	v := NetworkView{
+		Address:     n.address.Dots(),
+		Netmask:     n.mask.Dots(),
+		CIDRPrefix:  bits.OnesCount32(n.mask.Addr),
+		Network:     n.network.Dots(),
+		Broadcast:   n.broadcast.Dots(),
+		HostMin:     n.hostMin.Dots(),
+		HostMax:     n.hostMax.Dots(),
+		HostsPerNet: strconv.FormatUint(uint64(n.hostsPerNet.Addr), 10),
+	}
+	if printClass {
+		v.Class = GetClass(*n.address)
+	}
+	return v
+}
+
+func (n *Network) MarshalJSON() ([]byte, error) {
+	return json.Marshal(n.view(true))
+}
+
+func (n *Network) MarshalYAML() (interface{}, error) {
+	return n.view(true), nil
+}
+
+func (i *IPv4Address) MarshalJSON() ([]byte, error) {
+	return json.Marshal(i.Dots())
+}
+
+func (i *IPv4Address) MarshalYAML() (interface{}, error) {
+	return i.Dots(), nil
+}
+
+// Format renders n to writer according to opts.Mode. JSON and YAML both
+// read from the same NetworkView; text output is rendered by formatText
+// straight from n's fields.
+func (n *Network) Format(writer io.Writer, opts FormatOptions) error {
+	switch opts.Mode {
+	case FormatJSON:
+		data, err := json.MarshalIndent(n.view(opts.PrintClass), "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(writer, string(data))
+		return err
+
+	case FormatYAML:
+		data, err := yaml.Marshal(n.view(opts.PrintClass))
+		if err != nil {
+			return err
+		}
+		_, err = writer.Write(data)
+		return err
+
+	default:
+		return n.formatText(writer, opts)
+	}
+}
+
+func (n *Network) formatText(writer io.Writer, opts FormatOptions) error {
+	if opts.PrintDescription {
+		n.address.Print(writer, opts.Extended)
+		n.mask.Print(writer, opts.Extended)
+		fmt.Fprintf(writer, "CIDR Prefix:\t/%d\n", bits.OnesCount32(n.mask.Addr))
+	}
+	n.network.Print(writer, opts.Extended)
+	if opts.PrintClass {
+		fmt.Fprintf(writer, "CLASS %s\n", GetClass(*n.address))
+	}
+	n.hostMin.Print(writer, opts.Extended)
+	n.hostMax.Print(writer, opts.Extended)
+	n.broadcast.Print(writer, opts.Extended)
+	fmt.Fprintf(writer, "Hosts/Net:\t%d\n", n.hostsPerNet.Addr)
+	return nil
+}
+
+// printNetwork is the single call site main() uses to emit a network in
+// whichever format the -o flag selected. In JSON and YAML the subnets (if
+// any) are included in the output; in text mode only the network itself is
+// printed and subnets is ignored.
+func printNetwork(writer io.Writer, network *Network, opts FormatOptions, subnets []Network) {
+	if opts.Mode == FormatText {
+		network.Print(writer, opts.PrintDescription, opts.Extended, opts.PrintClass)
+		return
+	}
+
+	view := network.view(opts.PrintClass)
+	for _, subnet := range subnets {
+		view.Subnets = append(view.Subnets, subnet.view(false))
+	}
+
+	switch opts.Mode {
+	case FormatJSON:
+		data, err := json.MarshalIndent(view, "", "  ")
+		checkError(err)
+		fmt.Fprintln(writer, string(data))
+
+	case FormatYAML:
+		data, err := yaml.Marshal(view)
+		checkError(err)
+		writer.Write(data)
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func TestNetworkFormatJSON(t *testing.T) {
+	network := NewNetwork(IPv4mustParse("192.168.0.1"), IPv4mustParse("255.255.255.0"))
+
+	buf := bytes.Buffer{}
+	err := network.Format(&buf, FormatOptions{Mode: FormatJSON, PrintClass: true})
+	assert.NoError(t, err)
+
+	var view NetworkView
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &view))
+
+	assert.Equal(t, "192.168.0.1", view.Address)
+	assert.Equal(t, "255.255.255.0", view.Netmask)
+	assert.Equal(t, 24, view.CIDRPrefix)
+	assert.Equal(t, "192.168.0.0", view.Network)
+	assert.Equal(t, "192.168.0.255", view.Broadcast)
+	assert.Equal(t, "192.168.0.1", view.HostMin)
+	assert.Equal(t, "192.168.0.254", view.HostMax)
+	assert.Equal(t, "254", view.HostsPerNet)
+	assert.Equal(t, "C", view.Class)
+}
+
+func TestNetworkFormatYAML(t *testing.T) {
+	network := NewNetwork(IPv4mustParse("10.0.0.1"), IPv4mustParse("255.0.0.0"))
+
+	buf := bytes.Buffer{}
+	err := network.Format(&buf, FormatOptions{Mode: FormatYAML})
+	assert.NoError(t, err)
+
+	var view NetworkView
+	assert.NoError(t, yaml.Unmarshal(buf.Bytes(), &view))
+
+	assert.Equal(t, "10.0.0.1", view.Address)
+	assert.Equal(t, "10.0.0.0", view.Network)
+	assert.Equal(t, "", view.Class)
+}
+
+func TestNetworkFormatTextUnchanged(t *testing.T) {
+	network := NewNetwork(IPv4mustParse("192.168.0.1"), IPv4mustParse("255.255.255.0"))
+
+	viaPrint := bytes.Buffer{}
+	network.Print(&viaPrint, true, false, false)
+
+	viaFormat := bytes.Buffer{}
+	err := network.Format(&viaFormat, FormatOptions{Mode: FormatText, PrintDescription: true})
+	assert.NoError(t, err)
+
+	assert.Equal(t, viaPrint.String(), viaFormat.String())
+}
+
+func TestParseOutputMode(t *testing.T) {
+	testCases := []struct {
+		input       string
+		expected    OutputMode
+		expectedErr bool
+	}{
+		{input: "text", expected: FormatText},
+		{input: "json", expected: FormatJSON},
+		{input: "yaml", expected: FormatYAML},
+		{input: "xml", expectedErr: true},
+	}
+
+	for _, tC := range testCases {
+		actual, err := ParseOutputMode(tC.input)
+		if tC.expectedErr {
+			assert.Error(t, err)
+			continue
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, tC.expected, actual)
+	}
+}
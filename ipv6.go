@@ -0,0 +1,354 @@
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/big"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+// Uint128 represents a 128-bit unsigned integer as two 64-bit halves, the
+// same approach net/netip uses to back an IPv6 address without allocating.
+type Uint128 struct {
+	Hi, Lo uint64
+}
+
+func Uint128From16(b [16]byte) Uint128 {
+	return Uint128{
+		Hi: binary.BigEndian.Uint64(b[:8]),
+		Lo: binary.BigEndian.Uint64(b[8:]),
+	}
+}
+
+func (u Uint128) Bytes() [16]byte {
+	var b [16]byte
+	binary.BigEndian.PutUint64(b[:8], u.Hi)
+	binary.BigEndian.PutUint64(b[8:], u.Lo)
+	return b
+}
+
+func (u Uint128) Groups() [8]uint16 {
+	b := u.Bytes()
+	var g [8]uint16
+	for i := range g {
+		g[i] = binary.BigEndian.Uint16(b[i*2:])
+	}
+	return g
+}
+
+func (u Uint128) And(v Uint128) Uint128 { return Uint128{u.Hi & v.Hi, u.Lo & v.Lo} }
+func (u Uint128) Or(v Uint128) Uint128  { return Uint128{u.Hi | v.Hi, u.Lo | v.Lo} }
+func (u Uint128) Xor(v Uint128) Uint128 { return Uint128{u.Hi ^ v.Hi, u.Lo ^ v.Lo} }
+func (u Uint128) Not() Uint128          { return Uint128{^u.Hi, ^u.Lo} }
+
+func (u Uint128) AddOne() Uint128 {
+	lo := u.Lo + 1
+	hi := u.Hi
+	if lo == 0 {
+		hi++
+	}
+	return Uint128{hi, lo}
+}
+
+func (u Uint128) Cmp(v Uint128) int {
+	switch {
+	case u.Hi != v.Hi:
+		if u.Hi < v.Hi {
+			return -1
+		}
+		return 1
+	case u.Lo < v.Lo:
+		return -1
+	case u.Lo > v.Lo:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Big renders u as a big.Int, used to print host counts that overflow
+// every machine integer type once the prefix gets short enough.
+func (u Uint128) Big() *big.Int {
+	b := u.Bytes()
+	return new(big.Int).SetBytes(b[:])
+}
+
+func popcount128(u Uint128) int {
+	return bits.OnesCount64(u.Hi) + bits.OnesCount64(u.Lo)
+}
+
+func prefixToMask6(prefix uint32) Uint128 {
+	switch {
+	case prefix >= 128:
+		return Uint128{^uint64(0), ^uint64(0)}
+	case prefix == 0:
+		return Uint128{}
+	case prefix <= 64:
+		return Uint128{Hi: ^uint64(0) << (64 - prefix)}
+	default:
+		return Uint128{Hi: ^uint64(0), Lo: ^uint64(0) << (128 - prefix)}
+	}
+}
+
+// ParseIPv6 parses the full, "::"-compressed, and 4-in-6 textual forms of an
+// IPv6 address into a Uint128.
+func ParseIPv6(addrString string) (Uint128, error) {
+	if addrString == "" {
+		return Uint128{}, ErrInvalidSyntax
+	}
+
+	var headPart, tailPart string
+	doubleColon := strings.Count(addrString, "::")
+
+	switch {
+	case doubleColon > 1:
+		return Uint128{}, ErrInvalidSyntax
+	case doubleColon == 1:
+		halves := strings.SplitN(addrString, "::", 2)
+		headPart, tailPart = halves[0], halves[1]
+	default:
+		headPart = addrString
+	}
+
+	var head, tail []string
+	if headPart != "" {
+		head = strings.Split(headPart, ":")
+	}
+	if tailPart != "" {
+		tail = strings.Split(tailPart, ":")
+	}
+
+	// The 4-in-6 dotted form is only valid in the group that is genuinely
+	// the address's last hextet: the tail when "::" compressed the rest,
+	// or the head itself when there's no compression at all. A dotted
+	// group anywhere else (e.g. the head of "1.2.3.4::1") is invalid.
+	var err error
+	if doubleColon == 1 {
+		for _, g := range head {
+			if strings.Contains(g, ".") {
+				return Uint128{}, ErrInvalidSyntax
+			}
+		}
+		tail, err = expandEmbeddedIPv4(tail)
+		if err != nil {
+			return Uint128{}, err
+		}
+	} else {
+		head, err = expandEmbeddedIPv4(head)
+		if err != nil {
+			return Uint128{}, err
+		}
+	}
+
+	total := len(head) + len(tail)
+	if doubleColon == 1 {
+		if total > 7 {
+			return Uint128{}, ErrInvalidSyntax
+		}
+	} else if total != 8 {
+		return Uint128{}, ErrInvalidSyntax
+	}
+
+	var groups [8]uint16
+	for i, g := range head {
+		v, err := strconv.ParseUint(g, 16, 16)
+		if err != nil {
+			return Uint128{}, ErrInvalidSyntax
+		}
+		groups[i] = uint16(v)
+	}
+	offset := 8 - len(tail)
+	for i, g := range tail {
+		v, err := strconv.ParseUint(g, 16, 16)
+		if err != nil {
+			return Uint128{}, ErrInvalidSyntax
+		}
+		groups[offset+i] = uint16(v)
+	}
+
+	var b [16]byte
+	for i, g := range groups {
+		binary.BigEndian.PutUint16(b[i*2:], g)
+	}
+	return Uint128From16(b), nil
+}
+
+// expandEmbeddedIPv4 rewrites a trailing dotted-decimal group (the 4-in-6
+// form, e.g. "::ffff:192.0.2.1") into its two equivalent hextets.
+func expandEmbeddedIPv4(groups []string) ([]string, error) {
+	if len(groups) == 0 {
+		return groups, nil
+	}
+	last := groups[len(groups)-1]
+	if !strings.Contains(last, ".") {
+		return groups, nil
+	}
+
+	v4, err := parseEmbeddedIPv4(last)
+	if err != nil {
+		return nil, ErrInvalidSyntax
+	}
+	hi := strconv.FormatUint(uint64(v4>>16), 16)
+	lo := strconv.FormatUint(uint64(v4&0xffff), 16)
+	return append(groups[:len(groups)-1], hi, lo), nil
+}
+
+// parseEmbeddedIPv4 is like IPv4ToInt but, unlike it, rejects anything other
+// than exactly 4 octets — IPv4ToInt trusts binary.BigEndian.Uint32 to read
+// the first 4 bytes of whatever it's given, which panics on a short slice
+// and silently truncates a long one.
+func parseEmbeddedIPv4(s string) (uint32, error) {
+	octets := strings.Split(s, ".")
+	if len(octets) != 4 {
+		return 0, ErrInvalidSyntax
+	}
+
+	bytes := make([]byte, 0, 4)
+	for _, octetString := range octets {
+		if octetString == "" {
+			return 0, ErrInvalidSyntax
+		}
+
+		val, err := strconv.ParseUint(octetString, 10, 0)
+		if err != nil {
+			return 0, convertStrconvError(err.(*strconv.NumError).Unwrap())
+		}
+		if val > 255 {
+			return 0, ErrOutOfRange
+		}
+
+		bytes = append(bytes, byte(val))
+	}
+
+	return binary.BigEndian.Uint32(bytes), nil
+}
+
+func ParseMask6(mask string) (Uint128, error) {
+	if len(mask) < 2 || mask[0] != '/' {
+		return Uint128{}, ErrMaskParse
+	}
+
+	prefix, err := strconv.ParseUint(mask[1:], 10, 32)
+	if err != nil || prefix > 128 {
+		return Uint128{}, ErrMaskParse
+	}
+	return prefixToMask6(uint32(prefix)), nil
+}
+
+type IPv6Address struct {
+	Addr        Uint128
+	Description string
+}
+
+// Text renders the address in its canonical compressed form, collapsing the
+// longest run of zero groups into "::" the way net/netip does.
+func (i *IPv6Address) Text() string {
+	groups := i.Addr.Groups()
+	hex := make([]string, 8)
+	for idx, g := range groups {
+		hex[idx] = strconv.FormatUint(uint64(g), 16)
+	}
+
+	bestStart, bestLen := -1, 1
+	curStart, curLen := -1, 0
+	for idx, g := range groups {
+		if g == 0 {
+			if curStart == -1 {
+				curStart = idx
+			}
+			curLen++
+			continue
+		}
+		if curLen > bestLen {
+			bestStart, bestLen = curStart, curLen
+		}
+		curStart, curLen = -1, 0
+	}
+	if curLen > bestLen {
+		bestStart, bestLen = curStart, curLen
+	}
+
+	if bestStart == -1 {
+		return strings.Join(hex, ":")
+	}
+
+	left := strings.Join(hex[:bestStart], ":")
+	right := strings.Join(hex[bestStart+bestLen:], ":")
+	return left + "::" + right
+}
+
+func (i *IPv6Address) String() string {
+	return i.Text()
+}
+
+func (i *IPv6Address) Bits() string {
+	b := i.Addr.Bytes()
+	groups := make([]string, 8)
+	for idx := 0; idx < 8; idx++ {
+		groups[idx] = fmt.Sprintf("%08b%08b", b[idx*2], b[idx*2+1])
+	}
+	return strings.Join(groups, ":")
+}
+
+func (i *IPv6Address) Print(writer io.Writer, extended bool) {
+	if extended {
+		fmt.Fprintf(writer, "%s:\t%s\t%s\n", i.Description, i.Text(), i.Bits())
+		return
+	}
+	fmt.Fprintf(writer, "%s:\t%s\n", i.Description, i.Text())
+}
+
+// Network6 is the IPv6 counterpart of Network. IPv6 has no reserved
+// broadcast address, so HostMax is the last address of the range rather than
+// one below it, and the host count is a big.Int since it routinely overflows
+// every machine integer type (a /48 alone has 2^80 addresses).
+type Network6 struct {
+	address     *IPv6Address
+	mask        *IPv6Address
+	network     *IPv6Address
+	hostMin     *IPv6Address
+	hostMax     *IPv6Address
+	hostsPerNet *big.Int
+}
+
+func NewNetwork6(address, mask Uint128) *Network6 {
+	network := address.And(mask)
+	hostMin := network.AddOne()
+	hostMax := network.Or(mask.Not())
+
+	hostCount := new(big.Int).Lsh(big.NewInt(1), uint(128-popcount128(mask)))
+	hostCount.Sub(hostCount, big.NewInt(2))
+	if hostCount.Sign() < 0 {
+		hostCount.SetInt64(0)
+	}
+
+	return &Network6{
+		address:     &IPv6Address{address, "Address"},
+		mask:        &IPv6Address{mask, "Netmask"},
+		network:     &IPv6Address{network, "Network"},
+		hostMin:     &IPv6Address{hostMin, "HostMin"},
+		hostMax:     &IPv6Address{hostMax, "HostMax"},
+		hostsPerNet: hostCount,
+	}
+}
+
+func (n *Network6) Print(writer io.Writer, printDescription, extended bool) {
+	if printDescription {
+		n.address.Print(writer, extended)
+		n.mask.Print(writer, extended)
+		fmt.Fprintf(writer, "CIDR Prefix:\t/%d\n", popcount128(n.mask.Addr))
+	}
+	n.network.Print(writer, extended)
+	n.hostMin.Print(writer, extended)
+	n.hostMax.Print(writer, extended)
+	fmt.Fprintf(writer, "Hosts/Net:\t%s\n", n.hostsPerNet.String())
+}
+
+// isIPv6Input reports whether addrString looks like an IPv6 address rather
+// than dotted-decimal IPv4, so the CLI can pick a family without a flag.
+func isIPv6Input(addrString string) bool {
+	return strings.Contains(addrString, ":")
+}
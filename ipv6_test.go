@@ -0,0 +1,130 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseIPv6(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		input       string
+		expected    Uint128
+		expectedErr error
+	}{
+		{
+			desc:        "Full form",
+			input:       "2001:0db8:0000:0000:0000:0000:0000:0001",
+			expected:    Uint128{Hi: 0x20010db800000000, Lo: 0x0000000000000001},
+			expectedErr: nil,
+		},
+		{
+			desc:        "Compressed form",
+			input:       "2001:db8::1",
+			expected:    Uint128{Hi: 0x20010db800000000, Lo: 0x0000000000000001},
+			expectedErr: nil,
+		},
+		{
+			desc:        "Unspecified address",
+			input:       "::",
+			expected:    Uint128{},
+			expectedErr: nil,
+		},
+		{
+			desc:        "4-in-6 form",
+			input:       "::ffff:192.0.2.1",
+			expected:    Uint128{Hi: 0, Lo: 0x0000ffffc0000201},
+			expectedErr: nil,
+		},
+		{
+			desc:        "Too many double colons",
+			input:       "2001::db8::1",
+			expected:    Uint128{},
+			expectedErr: ErrInvalidSyntax,
+		},
+		{
+			desc:        "Empty input fails",
+			input:       "",
+			expected:    Uint128{},
+			expectedErr: ErrInvalidSyntax,
+		},
+		{
+			desc:        "Short embedded IPv4 fails instead of panicking",
+			input:       "::1.2.3",
+			expected:    Uint128{},
+			expectedErr: ErrInvalidSyntax,
+		},
+		{
+			desc:        "Overlong embedded IPv4 is rejected, not truncated",
+			input:       "::1.2.3.4.5",
+			expected:    Uint128{},
+			expectedErr: ErrInvalidSyntax,
+		},
+		{
+			desc:        "Embedded IPv4 in a non-trailing group is rejected",
+			input:       "1.2.3.4::1",
+			expected:    Uint128{},
+			expectedErr: ErrInvalidSyntax,
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			actual, actualErr := ParseIPv6(tC.input)
+
+			assert.Equal(t, tC.expectedErr, actualErr)
+			assert.Equal(t, tC.expected, actual)
+		})
+	}
+}
+
+func TestIPv6Text(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		input    Uint128
+		expected string
+	}{
+		{
+			desc:     "Compresses longest zero run",
+			input:    Uint128{Hi: 0x20010db800000000, Lo: 0x0000000000000001},
+			expected: "2001:db8::1",
+		},
+		{
+			desc:     "Unspecified address",
+			input:    Uint128{},
+			expected: "::",
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			ip := IPv6Address{Addr: tC.input}
+			assert.Equal(t, tC.expected, ip.Text())
+		})
+	}
+}
+
+func TestNewNetwork6(t *testing.T) {
+	address, err := ParseIPv6("2001:db8::1")
+	assert.NoError(t, err)
+
+	mask, err := ParseMask6("/48")
+	assert.NoError(t, err)
+
+	network := NewNetwork6(address, mask)
+
+	buf := bytes.Buffer{}
+	network.Print(&buf, true, false)
+
+	expected := "Address:\t2001:db8::1\n" +
+		"Netmask:\tffff:ffff:ffff::\n" +
+		"CIDR Prefix:\t/48\n" +
+		"Network:\t2001:db8::\n" +
+		"HostMin:\t2001:db8::1\n" +
+		"HostMax:\t2001:db8:0:ffff:ffff:ffff:ffff:ffff\n" +
+		"Hosts/Net:\t1208925819614629174706174\n"
+
+	assert.Equal(t, expected, buf.String())
+}
@@ -155,20 +155,15 @@ func NewNetwork(address, mask uint32) *Network {
 	}
 }
 
+// Print renders n as human-readable text. It is a thin wrapper around
+// Format kept around since most call sites only ever want text output.
 func (n *Network) Print(writer io.Writer, printDescription, extended, printClass bool) {
-	if printDescription {
-		n.address.Print(writer, extended)
-		n.mask.Print(writer, extended)
-		fmt.Fprintf(writer, "CIDR Prefix:\t/%d\n", bits.OnesCount(uint(n.mask.Addr)))
-	}
-	n.network.Print(writer, extended)
-	if printClass {
-		fmt.Fprintf(writer, "CLASS %s\n", GetClass(*n.address))
-	}
-	n.hostMin.Print(writer, extended)
-	n.hostMax.Print(writer, extended)
-	n.broadcast.Print(writer, extended)
-	fmt.Fprintf(writer, "Hosts/Net:\t%d\n", n.hostsPerNet.Addr)
+	n.Format(writer, FormatOptions{
+		Mode:             FormatText,
+		PrintDescription: printDescription,
+		Extended:         extended,
+		PrintClass:       printClass,
+	})
 }
 
 func ParseMask(mask string) (uint32, error) {
@@ -238,17 +233,63 @@ func checkError(err error) {
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "lookup" {
+		runLookup(os.Args[2:])
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "aggregate" {
+		runAggregate(os.Args[2:])
+		return
+	}
+
 	extended := true
 
 	printExtended := flag.Bool("e", false, "Display extended output")
 	printClass := flag.Bool("c", false, "Display network class")
+	vlsmFlag := flag.String("vlsm", "", "Comma separated host-count requirements for VLSM subnetting, e.g. -vlsm 50,25,10,2")
+	outputFlag := flag.String("o", "text", "Output format: text|json|yaml")
 	flag.Parse()
 
+	outputMode, err := ParseOutputMode(*outputFlag)
+	checkError(err)
+
 	args := flag.Args()
 	argCount := len(args)
 
-	if argCount < 2 || argCount > 3 {
-		checkError(errors.New("Need at least 2 arguments"))
+	if argCount < 1 || argCount > 3 {
+		checkError(errors.New("Need at least 1 argument"))
+	}
+
+	if argCount == 1 {
+		prefix, err := ParsePrefix(args[0])
+		checkError(err)
+
+		if prefix.Addr().Is6() {
+			network6 := NewNetwork6(prefix.Addr().As16(), prefix.mask())
+			network6.Print(os.Stdout, true, *printExtended)
+			return
+		}
+
+		network := NewNetwork(prefix.Addr().As4(), uint32(prefix.mask().Lo))
+		printNetwork(os.Stdout, network, FormatOptions{Mode: outputMode, PrintDescription: true, Extended: *printExtended, PrintClass: *printClass}, nil)
+
+		if *vlsmFlag != "" {
+			checkError(printVLSM(os.Stdout, network, *vlsmFlag))
+		}
+		return
+	}
+
+	if isIPv6Input(args[0]) {
+		address6, err := ParseIPv6(args[0])
+		checkError(err)
+
+		mask6, err := ParseMask6(args[1])
+		checkError(err)
+
+		network6 := NewNetwork6(address6, mask6)
+		network6.Print(os.Stdout, true, *printExtended)
+		return
 	}
 
 	address, err := IPv4ToInt(args[0])
@@ -258,7 +299,25 @@ func main() {
 	checkError(err)
 
 	network := NewNetwork(address, mask)
-	network.Print(os.Stdout, true, *printExtended, *printClass)
+	opts := FormatOptions{Mode: outputMode, PrintDescription: true, Extended: *printExtended, PrintClass: *printClass}
+
+	if argCount == 3 && outputMode != FormatText {
+		subnetMask, err := ParseMask(args[2])
+		checkError(err)
+
+		subnets, err := CreateSubnets(address, mask, subnetMask)
+		checkError(err)
+
+		printNetwork(os.Stdout, network, opts, subnets)
+		return
+	}
+
+	printNetwork(os.Stdout, network, opts, nil)
+
+	if *vlsmFlag != "" {
+		checkError(printVLSM(os.Stdout, network, *vlsmFlag))
+		return
+	}
 
 	if argCount == 3 {
 		subnetMask, err := ParseMask(args[2])
@@ -281,3 +340,41 @@ func main() {
 
 	}
 }
+
+// runLookup implements `ipcalc lookup <file-of-cidrs> <addr>`: it loads every
+// CIDR in the file into a Ranger and prints the networks that contain addr,
+// most specific match first.
+func runLookup(args []string) {
+	if len(args) != 2 {
+		checkError(errors.New("Usage: ipcalc lookup <file-of-cidrs> <addr>"))
+	}
+
+	ranger, err := loadRangerFromFile(args[0])
+	checkError(err)
+
+	var matched []string
+
+	if isIPv6Input(args[1]) {
+		addr6, err := ParseIPv6(args[1])
+		checkError(err)
+
+		for _, network := range ranger.ContainingNetworks6(AddrFrom16(addr6.Bytes())) {
+			matched = append(matched, fmt.Sprintf("%s/%d", network.network.Text(), popcount128(network.mask.Addr)))
+		}
+	} else {
+		addr, err := IPv4ToInt(args[1])
+		checkError(err)
+
+		for _, network := range ranger.ContainingNetworks(AddrFrom4(addr)) {
+			matched = append(matched, fmt.Sprintf("%s/%d", network.network.Dots(), bits.OnesCount32(network.mask.Addr)))
+		}
+	}
+
+	if len(matched) == 0 {
+		fmt.Println("No matching networks found")
+		return
+	}
+	for _, m := range matched {
+		fmt.Println(m)
+	}
+}
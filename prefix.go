@@ -0,0 +1,191 @@
+package main
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Addr holds a single IPv4 or IPv6 address, mirroring the shape of
+// net/netip.Addr closely enough that Prefix can stay family-agnostic.
+type Addr struct {
+	addr Uint128
+	isV6 bool
+}
+
+func AddrFrom4(v uint32) Addr {
+	return Addr{addr: Uint128{Lo: uint64(v)}}
+}
+
+func AddrFrom16(b [16]byte) Addr {
+	return Addr{addr: Uint128From16(b), isV6: true}
+}
+
+func (a Addr) Is6() bool     { return a.isV6 }
+func (a Addr) As4() uint32   { return uint32(a.addr.Lo) }
+func (a Addr) As16() Uint128 { return a.addr }
+
+func (a Addr) String() string {
+	if a.isV6 {
+		return (&IPv6Address{Addr: a.addr}).Text()
+	}
+	return (&IPv4Address{Addr: uint32(a.addr.Lo)}).Dots()
+}
+
+// Prefix is an address plus a prefix length, similar to net/netip.Prefix.
+// It lets the CLI and the rest of the package pass "192.168.1.10/24" or
+// "2001:db8::/48" around as a single value instead of address+mask pairs.
+type Prefix struct {
+	addr Addr
+	bits int
+}
+
+func PrefixFrom(addr Addr, bits int) Prefix {
+	return Prefix{addr: addr, bits: bits}
+}
+
+func (p Prefix) Addr() Addr { return p.addr }
+func (p Prefix) Bits() int  { return p.bits }
+
+func (p Prefix) IsValid() bool {
+	if p.addr.isV6 {
+		return p.bits >= 0 && p.bits <= 128
+	}
+	return p.bits >= 0 && p.bits <= 32
+}
+
+func (p Prefix) mask() Uint128 {
+	if p.addr.isV6 {
+		return prefixToMask6(uint32(p.bits))
+	}
+	return Uint128{Lo: uint64(prefixToMask(uint32(p.bits)))}
+}
+
+// Masked returns p with its address's host bits zeroed.
+func (p Prefix) Masked() Prefix {
+	m := p.mask()
+	return Prefix{addr: Addr{addr: p.addr.addr.And(m), isV6: p.addr.isV6}, bits: p.bits}
+}
+
+func (p Prefix) Contains(addr Addr) bool {
+	if !p.IsValid() || addr.isV6 != p.addr.isV6 {
+		return false
+	}
+	m := p.mask()
+	return p.addr.addr.And(m) == addr.addr.And(m)
+}
+
+func (p Prefix) Overlaps(other Prefix) bool {
+	if !p.IsValid() || !other.IsValid() || p.addr.isV6 != other.addr.isV6 {
+		return false
+	}
+
+	minBits := p.bits
+	if other.bits < minBits {
+		minBits = other.bits
+	}
+
+	shorter := p
+	shorter.bits = minBits
+	m := shorter.mask()
+	return p.addr.addr.And(m) == other.addr.addr.And(m)
+}
+
+func (p Prefix) String() string {
+	if !p.IsValid() {
+		return "invalid Prefix"
+	}
+	return fmt.Sprintf("%s/%d", p.addr.String(), p.bits)
+}
+
+func (p Prefix) MarshalText() ([]byte, error) {
+	if !p.IsValid() {
+		return []byte{}, nil
+	}
+	return []byte(p.String()), nil
+}
+
+func (p *Prefix) UnmarshalText(text []byte) error {
+	parsed, err := ParsePrefix(string(text))
+	if err != nil {
+		return err
+	}
+	*p = parsed
+	return nil
+}
+
+func (p Prefix) MarshalJSON() ([]byte, error) {
+	text, err := p.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(text))
+}
+
+func (p *Prefix) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	return p.UnmarshalText([]byte(s))
+}
+
+// ParsePrefix parses a combined address/prefix-length string such as
+// "192.168.1.10/24" or "2001:db8::1/64". Unlike IPv4ToInt, the IPv4 form here
+// rejects octets with leading zeros (e.g. "010.0.0.1") to match the
+// strictness of net/netip.
+func ParsePrefix(s string) (Prefix, error) {
+	slash := strings.LastIndexByte(s, '/')
+	if slash < 0 {
+		return Prefix{}, ErrInvalidSyntax
+	}
+	addrPart, bitsPart := s[:slash], s[slash+1:]
+
+	if isIPv6Input(addrPart) {
+		addr, err := ParseIPv6(addrPart)
+		if err != nil {
+			return Prefix{}, err
+		}
+		bits, err := strconv.Atoi(bitsPart)
+		if err != nil || bits < 0 || bits > 128 {
+			return Prefix{}, ErrMaskParse
+		}
+		return Prefix{addr: AddrFrom16(addr.Bytes()), bits: bits}, nil
+	}
+
+	addr, err := parseStrictIPv4(addrPart)
+	if err != nil {
+		return Prefix{}, err
+	}
+	bits, err := strconv.Atoi(bitsPart)
+	if err != nil || bits < 0 || bits > 32 {
+		return Prefix{}, ErrMaskParse
+	}
+	return Prefix{addr: AddrFrom4(addr), bits: bits}, nil
+}
+
+// parseStrictIPv4 is like IPv4ToInt but rejects octets with leading zeros,
+// since ParsePrefix is held to net/netip's stricter parsing rules.
+func parseStrictIPv4(s string) (uint32, error) {
+	octets := strings.Split(s, ".")
+	if len(octets) != 4 {
+		return 0, ErrInvalidSyntax
+	}
+
+	bytes := make([]byte, 0, 4)
+	for _, octet := range octets {
+		if len(octet) == 0 || (len(octet) > 1 && octet[0] == '0') {
+			return 0, ErrInvalidSyntax
+		}
+
+		val, err := strconv.ParseUint(octet, 10, 8)
+		if err != nil {
+			return 0, convertStrconvError(err.(*strconv.NumError).Unwrap())
+		}
+		bytes = append(bytes, byte(val))
+	}
+
+	return binary.BigEndian.Uint32(bytes), nil
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePrefix(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		input       string
+		expected    string
+		expectedErr error
+	}{
+		{
+			desc:        "IPv4 prefix",
+			input:       "192.168.1.10/24",
+			expected:    "192.168.1.10/24",
+			expectedErr: nil,
+		},
+		{
+			desc:        "IPv6 prefix",
+			input:       "2001:db8::1/64",
+			expected:    "2001:db8::1/64",
+			expectedErr: nil,
+		},
+		{
+			desc:        "Missing slash fails",
+			input:       "192.168.1.10",
+			expectedErr: ErrInvalidSyntax,
+		},
+		{
+			desc:        "Prefix length out of range fails",
+			input:       "192.168.1.10/33",
+			expectedErr: ErrMaskParse,
+		},
+		{
+			desc:        "v6 prefix length out of range fails",
+			input:       "2001:db8::1/129",
+			expectedErr: ErrMaskParse,
+		},
+		{
+			desc:        "Leading zero octet fails",
+			input:       "010.0.0.1/24",
+			expectedErr: ErrInvalidSyntax,
+		},
+		{
+			desc:        "Malformed embedded IPv4 fails instead of panicking",
+			input:       "::1.2.3/64",
+			expectedErr: ErrInvalidSyntax,
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			actual, actualErr := ParsePrefix(tC.input)
+
+			assert.Equal(t, tC.expectedErr, actualErr)
+			if tC.expectedErr == nil {
+				assert.Equal(t, tC.expected, actual.String())
+			}
+		})
+	}
+}
+
+func TestPrefixMasked(t *testing.T) {
+	p, err := ParsePrefix("192.168.1.10/24")
+	assert.NoError(t, err)
+
+	assert.Equal(t, "192.168.1.0/24", p.Masked().String())
+}
+
+func TestPrefixContains(t *testing.T) {
+	p, err := ParsePrefix("192.168.1.0/24")
+	assert.NoError(t, err)
+
+	inside, err := ParsePrefix("192.168.1.42/32")
+	assert.NoError(t, err)
+	outside, err := ParsePrefix("192.168.2.42/32")
+	assert.NoError(t, err)
+
+	assert.True(t, p.Contains(inside.Addr()))
+	assert.False(t, p.Contains(outside.Addr()))
+}
+
+func TestPrefixOverlaps(t *testing.T) {
+	a, err := ParsePrefix("192.168.0.0/23")
+	assert.NoError(t, err)
+	b, err := ParsePrefix("192.168.1.0/24")
+	assert.NoError(t, err)
+	c, err := ParsePrefix("192.168.4.0/24")
+	assert.NoError(t, err)
+
+	assert.True(t, a.Overlaps(b))
+	assert.False(t, a.Overlaps(c))
+}
+
+func TestPrefixJSON(t *testing.T) {
+	p, err := ParsePrefix("192.168.1.10/24")
+	assert.NoError(t, err)
+
+	data, err := json.Marshal(p)
+	assert.NoError(t, err)
+	assert.Equal(t, `"192.168.1.10/24"`, string(data))
+
+	var roundTrip Prefix
+	assert.NoError(t, json.Unmarshal(data, &roundTrip))
+	assert.Equal(t, p.String(), roundTrip.String())
+}
@@ -0,0 +1,323 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"sort"
+)
+
+// rangerNode is a node in a binary, path-compressed radix tree keyed on
+// address bits. The path from the root to a node spans node.prefix.Bits()
+// bits; children only exist where two inserted prefixes first diverge, so a
+// chain of single-child nodes is collapsed into one edge instead of one node
+// per bit, the way a PATRICIA trie is usually drawn.
+type rangerNode struct {
+	prefix   Prefix
+	hasValue bool
+	left     *rangerNode // next bit (at position prefix.Bits()) is 0
+	right    *rangerNode // next bit (at position prefix.Bits()) is 1
+}
+
+// Ranger is a longest-prefix-match container for CIDR blocks, used to answer
+// "which subnet(s) contain this address" the way a routing table does.
+// IPv4 and IPv6 prefixes are kept in separate trees since they don't share
+// an address space.
+type Ranger struct {
+	root4 *rangerNode
+	root6 *rangerNode
+}
+
+func NewRanger() *Ranger {
+	return &Ranger{}
+}
+
+func (r *Ranger) Insert(n Network) {
+	r.insertPrefix(Prefix{addr: AddrFrom4(n.network.Addr), bits: popcount128(Uint128{Lo: uint64(n.mask.Addr)})})
+}
+
+func (r *Ranger) Insert6(n Network6) {
+	r.insertPrefix(Prefix{addr: AddrFrom16(n.network.Addr.Bytes()), bits: popcount128(n.mask.Addr)})
+}
+
+func (r *Ranger) insertPrefix(p Prefix) {
+	p = p.Masked()
+	if p.addr.isV6 {
+		r.root6 = rangerInsert(r.root6, p)
+	} else {
+		r.root4 = rangerInsert(r.root4, p)
+	}
+}
+
+// Remove detaches the network previously inserted at exactly this prefix.
+// It does not compact the tree afterwards; a node with no attached network
+// is just as cheap to walk past as a leaf.
+func (r *Ranger) Remove(p Prefix) {
+	p = p.Masked()
+	node := r.root4
+	if p.addr.isV6 {
+		node = r.root6
+	}
+
+	for node != nil {
+		common := commonPrefixLen(node.prefix, p)
+		switch {
+		case common == node.prefix.bits && node.prefix.bits == p.bits:
+			node.hasValue = false
+			return
+		case common == node.prefix.bits && node.prefix.bits < p.bits:
+			if bitAt(p, node.prefix.bits) == 0 {
+				node = node.left
+			} else {
+				node = node.right
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (r *Ranger) Contains(addr Addr) bool {
+	return len(r.matches(addr)) > 0
+}
+
+func (r *Ranger) ContainingNetworks(addr Addr) []Network {
+	var out []Network
+	for _, p := range r.matches(addr) {
+		if p.addr.isV6 {
+			continue
+		}
+		out = append(out, *NewNetwork(p.addr.As4(), uint32(p.mask().Lo)))
+	}
+	return out
+}
+
+func (r *Ranger) ContainingNetworks6(addr Addr) []Network6 {
+	var out []Network6
+	for _, p := range r.matches(addr) {
+		if !p.addr.isV6 {
+			continue
+		}
+		out = append(out, *NewNetwork6(p.addr.As16(), p.mask()))
+	}
+	return out
+}
+
+func (r *Ranger) CoveredNetworks(prefix Prefix) []Network {
+	var matched []Prefix
+	rangerCovered(r.root4, prefix.Masked(), &matched)
+
+	out := make([]Network, 0, len(matched))
+	for _, p := range matched {
+		out = append(out, *NewNetwork(p.addr.As4(), uint32(p.mask().Lo)))
+	}
+	return out
+}
+
+func (r *Ranger) CoveredNetworks6(prefix Prefix) []Network6 {
+	var matched []Prefix
+	rangerCovered(r.root6, prefix.Masked(), &matched)
+
+	out := make([]Network6, 0, len(matched))
+	for _, p := range matched {
+		out = append(out, *NewNetwork6(p.addr.As16(), p.mask()))
+	}
+	return out
+}
+
+// matches returns every inserted prefix that contains addr, most specific
+// (longest prefix) first.
+func (r *Ranger) matches(addr Addr) []Prefix {
+	node := r.root4
+	if addr.isV6 {
+		node = r.root6
+	}
+
+	var out []Prefix
+	for node != nil {
+		if !prefixMatchesAddr(node.prefix, addr) {
+			break
+		}
+		if node.hasValue {
+			out = append(out, node.prefix)
+		}
+		if node.prefix.bits >= addrWidth(addr) {
+			break
+		}
+		if bitAtAddr(addr, node.prefix.bits) == 0 {
+			node = node.left
+		} else {
+			node = node.right
+		}
+	}
+
+	sort.SliceStable(out, func(i, j int) bool { return out[i].bits > out[j].bits })
+	return out
+}
+
+func rangerInsert(node *rangerNode, p Prefix) *rangerNode {
+	if node == nil {
+		return &rangerNode{prefix: p, hasValue: true}
+	}
+
+	common := commonPrefixLen(node.prefix, p)
+
+	switch {
+	case common == node.prefix.bits && common == p.bits:
+		node.hasValue = true
+		return node
+
+	case common == node.prefix.bits:
+		if bitAt(p, node.prefix.bits) == 0 {
+			node.left = rangerInsert(node.left, p)
+		} else {
+			node.right = rangerInsert(node.right, p)
+		}
+		return node
+
+	case common == p.bits:
+		parent := &rangerNode{prefix: p, hasValue: true}
+		if bitAt(node.prefix, p.bits) == 0 {
+			parent.left = node
+		} else {
+			parent.right = node
+		}
+		return parent
+
+	default:
+		branch := Prefix{addr: node.prefix.addr, bits: common}.Masked()
+		split := &rangerNode{prefix: branch}
+		if bitAt(node.prefix, common) == 0 {
+			split.left = node
+			split.right = &rangerNode{prefix: p, hasValue: true}
+		} else {
+			split.right = node
+			split.left = &rangerNode{prefix: p, hasValue: true}
+		}
+		return split
+	}
+}
+
+func rangerCovered(node *rangerNode, prefix Prefix, out *[]Prefix) {
+	if node == nil {
+		return
+	}
+
+	if node.prefix.bits >= prefix.bits {
+		if commonPrefixLen(node.prefix, prefix) >= prefix.bits {
+			rangerCollect(node, out)
+		}
+		return
+	}
+
+	if commonPrefixLen(node.prefix, prefix) != node.prefix.bits {
+		return
+	}
+
+	if bitAt(prefix, node.prefix.bits) == 0 {
+		rangerCovered(node.left, prefix, out)
+	} else {
+		rangerCovered(node.right, prefix, out)
+	}
+}
+
+func rangerCollect(node *rangerNode, out *[]Prefix) {
+	if node == nil {
+		return
+	}
+	if node.hasValue {
+		*out = append(*out, node.prefix)
+	}
+	rangerCollect(node.left, out)
+	rangerCollect(node.right, out)
+}
+
+// normalizedKey places an address's bits at the top of a 128-bit space so a
+// single bit-indexing scheme covers both IPv4 (32 bits) and IPv6 (128 bits).
+func normalizedKey(p Prefix) Uint128 {
+	if p.addr.isV6 {
+		return p.addr.addr
+	}
+	return Uint128{Hi: uint64(p.addr.As4()) << 32}
+}
+
+func addrWidth(addr Addr) int {
+	if addr.isV6 {
+		return 128
+	}
+	return 32
+}
+
+func bitAtKey(key Uint128, pos int) uint8 {
+	if pos < 64 {
+		return uint8((key.Hi >> (63 - pos)) & 1)
+	}
+	return uint8((key.Lo >> (127 - pos)) & 1)
+}
+
+func bitAt(p Prefix, pos int) uint8 {
+	return bitAtKey(normalizedKey(p), pos)
+}
+
+func bitAtAddr(addr Addr, pos int) uint8 {
+	return bitAt(Prefix{addr: addr, bits: addrWidth(addr)}, pos)
+}
+
+func prefixMatchesAddr(p Prefix, addr Addr) bool {
+	if p.addr.isV6 != addr.isV6 {
+		return false
+	}
+	pk, ak := normalizedKey(p), normalizedKey(Prefix{addr: addr, bits: p.bits})
+	for i := 0; i < p.bits; i++ {
+		if bitAtKey(pk, i) != bitAtKey(ak, i) {
+			return false
+		}
+	}
+	return true
+}
+
+func commonPrefixLen(a, b Prefix) int {
+	maxLen := a.bits
+	if b.bits < maxLen {
+		maxLen = b.bits
+	}
+
+	ak, bk := normalizedKey(a), normalizedKey(b)
+	common := 0
+	for common < maxLen && bitAtKey(ak, common) == bitAtKey(bk, common) {
+		common++
+	}
+	return common
+}
+
+// loadRangerFromFile reads one CIDR per line (blank lines ignored) into a
+// fresh Ranger, for the `ipcalc lookup` subcommand.
+func loadRangerFromFile(path string) (*Ranger, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	ranger := NewRanger()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+
+		prefix, err := ParsePrefix(line)
+		if err != nil {
+			return nil, err
+		}
+
+		if prefix.addr.isV6 {
+			ranger.Insert6(*NewNetwork6(prefix.addr.As16(), prefix.mask()))
+		} else {
+			ranger.Insert(*NewNetwork(prefix.addr.As4(), uint32(prefix.mask().Lo)))
+		}
+	}
+
+	return ranger, scanner.Err()
+}
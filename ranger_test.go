@@ -0,0 +1,85 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func mustPrefix(t *testing.T, s string) Prefix {
+	t.Helper()
+	p, err := ParsePrefix(s)
+	assert.NoError(t, err)
+	return p
+}
+
+func TestRangerContainingNetworksLongestMatch(t *testing.T) {
+	ranger := NewRanger()
+
+	for _, cidr := range []string{"10.0.0.0/8", "10.1.0.0/16", "10.1.2.0/24"} {
+		p := mustPrefix(t, cidr)
+		ranger.Insert(*NewNetwork(p.addr.As4(), uint32(p.mask().Lo)))
+	}
+
+	addr, err := IPv4ToInt("10.1.2.42")
+	assert.NoError(t, err)
+
+	networks := ranger.ContainingNetworks(AddrFrom4(addr))
+	assert.Len(t, networks, 3)
+
+	// most specific match first
+	assert.Equal(t, "10.1.2.0", networks[0].network.Dots())
+	assert.Equal(t, "10.1.0.0", networks[1].network.Dots())
+	assert.Equal(t, "10.0.0.0", networks[2].network.Dots())
+}
+
+func TestRangerContains(t *testing.T) {
+	ranger := NewRanger()
+	p := mustPrefix(t, "192.168.1.0/24")
+	ranger.Insert(*NewNetwork(p.addr.As4(), uint32(p.mask().Lo)))
+
+	inside, err := IPv4ToInt("192.168.1.200")
+	assert.NoError(t, err)
+	outside, err := IPv4ToInt("192.168.2.1")
+	assert.NoError(t, err)
+
+	assert.True(t, ranger.Contains(AddrFrom4(inside)))
+	assert.False(t, ranger.Contains(AddrFrom4(outside)))
+}
+
+func TestRangerRemove(t *testing.T) {
+	ranger := NewRanger()
+	p := mustPrefix(t, "192.168.1.0/24")
+	ranger.Insert(*NewNetwork(p.addr.As4(), uint32(p.mask().Lo)))
+
+	addr, err := IPv4ToInt("192.168.1.1")
+	assert.NoError(t, err)
+	assert.True(t, ranger.Contains(AddrFrom4(addr)))
+
+	ranger.Remove(p)
+	assert.False(t, ranger.Contains(AddrFrom4(addr)))
+}
+
+func TestRangerCoveredNetworks(t *testing.T) {
+	ranger := NewRanger()
+	for _, cidr := range []string{"10.0.0.0/24", "10.0.1.0/24", "172.16.0.0/24"} {
+		p := mustPrefix(t, cidr)
+		ranger.Insert(*NewNetwork(p.addr.As4(), uint32(p.mask().Lo)))
+	}
+
+	covered := ranger.CoveredNetworks(mustPrefix(t, "10.0.0.0/8"))
+	assert.Len(t, covered, 2)
+}
+
+func TestRangerIPv6(t *testing.T) {
+	ranger := NewRanger()
+	p := mustPrefix(t, "2001:db8::/32")
+	ranger.Insert6(*NewNetwork6(p.addr.As16(), p.mask()))
+
+	addr, err := ParseIPv6("2001:db8::1")
+	assert.NoError(t, err)
+
+	networks := ranger.ContainingNetworks6(AddrFrom16(addr.Bytes()))
+	assert.Len(t, networks, 1)
+	assert.Equal(t, "2001:db8::", networks[0].network.Text())
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// CreateVLSMSubnets allocates one variable-length subnet per entry in
+// requests, each sized to the smallest prefix that can hold that many
+// hosts, packed back-to-back starting at base's network address. Unlike
+// CreateSubnets, the allocated prefixes need not be uniform.
+//
+// Requests are processed largest-first so bigger subnets don't get stranded
+// behind smaller ones that already consumed the aligned address space, but
+// the returned slice preserves the caller's original order.
+func CreateVLSMSubnets(base Network, requests []int) ([]Network, error) {
+	type pendingAlloc struct {
+		index int
+		hosts int
+	}
+
+	order := make([]pendingAlloc, len(requests))
+	for i, hosts := range requests {
+		order[i] = pendingAlloc{index: i, hosts: hosts}
+	}
+	sort.SliceStable(order, func(i, j int) bool { return order[i].hosts > order[j].hosts })
+
+	result := make([]Network, len(requests))
+	cursor := base.network.Addr
+
+	for _, alloc := range order {
+		prefix, err := hostsToPrefix(alloc.hosts)
+		if err != nil {
+			return nil, err
+		}
+
+		size := uint32(1) << (32 - prefix)
+		aligned := (cursor + size - 1) &^ (size - 1)
+
+		if uint64(aligned)+uint64(size)-1 > uint64(base.broadcast.Addr) {
+			return nil, ErrPrefixTooSmall
+		}
+
+		result[alloc.index] = *NewNetwork(aligned, prefixToMask(uint32(prefix)))
+		cursor = aligned + size
+	}
+
+	return result, nil
+}
+
+// hostsToPrefix returns the largest (i.e. smallest subnet) prefix length
+// whose usable host count (2^(32-p) - 2) still covers the request.
+func hostsToPrefix(hosts int) (int, error) {
+	for prefix := 32; prefix >= 0; prefix-- {
+		capacity := int64(1)<<uint(32-prefix) - 2
+		if capacity >= int64(hosts) {
+			return prefix, nil
+		}
+	}
+	return 0, ErrPrefixTooSmall
+}
+
+// wastedAddresses is the gap between a subnet's usable host count and the
+// number of hosts actually requested for it.
+func wastedAddresses(n Network, requested int) int {
+	return int(n.hostsPerNet.Addr) - requested
+}
+
+// parseVLSMRequests parses a comma separated list of host-count requirements,
+// e.g. "50,25,10,2", as accepted by the CLI's -vlsm flag.
+func parseVLSMRequests(spec string) ([]int, error) {
+	parts := strings.Split(spec, ",")
+	requests := make([]int, 0, len(parts))
+
+	for _, part := range parts {
+		hosts, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			return nil, ErrInvalidSyntax
+		}
+		requests = append(requests, hosts)
+	}
+
+	return requests, nil
+}
+
+// printVLSM parses spec, allocates VLSM subnets from base, and prints them
+// alongside a wasted-address-space summary.
+func printVLSM(writer io.Writer, base *Network, spec string) error {
+	requests, err := parseVLSMRequests(spec)
+	if err != nil {
+		return err
+	}
+
+	subnets, err := CreateVLSMSubnets(*base, requests)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(writer)
+	fmt.Fprintf(writer, "VLSM subnets for %d requested host count(s)\n\n", len(requests))
+
+	for i, subnet := range subnets {
+		fmt.Fprintf(writer, "%d. (%d hosts requested)\n", i+1, requests[i])
+		subnet.Print(writer, false, false, false)
+		fmt.Fprintf(writer, "Wasted:\t\t%d\n", wastedAddresses(subnet, requests[i]))
+		fmt.Fprintln(writer)
+	}
+
+	return nil
+}
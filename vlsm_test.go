@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"math/bits"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateVLSMSubnets(t *testing.T) {
+	base := *NewNetwork(IPv4mustParse("192.168.1.0"), IPv4mustParse("255.255.255.0"))
+
+	subnets, err := CreateVLSMSubnets(base, []int{50, 25, 10, 2})
+	assert.NoError(t, err)
+	assert.Len(t, subnets, 4)
+
+	// Requests come back in the caller's original order. This input is
+	// already largest-first, so it also matches the allocation order.
+	assert.Equal(t, "192.168.1.0", subnets[0].network.Dots())
+	assert.Equal(t, "/26", subnetCIDR(subnets[0]))
+
+	assert.Equal(t, "192.168.1.64", subnets[1].network.Dots())
+	assert.Equal(t, "/27", subnetCIDR(subnets[1]))
+
+	assert.Equal(t, "192.168.1.96", subnets[2].network.Dots())
+	assert.Equal(t, "/28", subnetCIDR(subnets[2]))
+
+	assert.Equal(t, "192.168.1.112", subnets[3].network.Dots())
+	assert.Equal(t, "/30", subnetCIDR(subnets[3]))
+}
+
+func TestCreateVLSMSubnetsOverflow(t *testing.T) {
+	base := *NewNetwork(IPv4mustParse("192.168.1.0"), IPv4mustParse("255.255.255.252"))
+
+	_, err := CreateVLSMSubnets(base, []int{50})
+	assert.Equal(t, ErrPrefixTooSmall, err)
+}
+
+func TestHostsToPrefix(t *testing.T) {
+	testCases := []struct {
+		hosts    int
+		expected int
+	}{
+		{hosts: 50, expected: 26},
+		{hosts: 25, expected: 27},
+		{hosts: 2, expected: 30},
+	}
+
+	for _, tC := range testCases {
+		actual, err := hostsToPrefix(tC.hosts)
+		assert.NoError(t, err)
+		assert.Equal(t, tC.expected, actual)
+	}
+}
+
+func subnetCIDR(n Network) string {
+	return fmt.Sprintf("/%d", bits.OnesCount32(n.mask.Addr))
+}
+
+func IPv4mustParse(s string) uint32 {
+	addr, err := IPv4ToInt(s)
+	if err != nil {
+		panic(err)
+	}
+	return addr
+}